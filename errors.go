@@ -7,11 +7,123 @@ import (
 	"path"
 	"runtime"
 	"strconv"
+	"time"
 )
 
 type stackType struct {
 	programCounters []uintptr
 	frames          *runtime.Frames
+
+	// filter, if set, is applied lazily (in formatVerboseStackTrace/toFrames)
+	// to drop frames the caller isn't interested in. See SetFrameFilter and
+	// WithFilter.
+	filter func(runtime.Frame) bool
+
+	// The fields below are only meaningful for a stackType created by
+	// NewWithStackTraceLazy/WrapWithStackTraceLazy; see lazy.go. seedFuncName
+	// and capturedAt are set at creation; staleCapture is derived once
+	// capture() finally runs, by checking whether seedFuncName is still
+	// anywhere on the newly captured stack, and captureDelay records how long
+	// after creation that was.
+	lazy            bool
+	pendingMaxDepth int
+	seedFuncName    string
+	capturedAt      time.Time
+	staleCapture    bool
+	captureDelay    time.Duration
+}
+
+func (s *stackType) include(frame runtime.Frame) bool {
+	return s.filter == nil || s.filter(frame)
+}
+
+// Frame is a single entry in a captured stack trace. It mirrors the subset of
+// runtime.Frame that callers need without forcing them to depend on the
+// runtime package themselves.
+type Frame struct {
+	Function string
+	File     string
+	Line     int
+	PC       uintptr
+}
+
+// StackTracer is implemented by errors created with NewWithStackTrace or
+// WrapWithStackTrace. It follows the same contract as github.com/pkg/errors,
+// so tooling that already knows how to pull a stack trace out of a
+// pkg/errors error (Sentry, Bugsnag, and friends) works against this package
+// too.
+type StackTracer interface {
+	StackTrace() []Frame
+}
+
+// capture performs the deferred runtime.Callers walk for a lazily-created
+// stack (see lazy.go); it's a no-op once programCounters is already set,
+// which is immediately true for every non-lazy stack. extraSkip accounts for
+// the frames between capture's caller and whatever external code triggered
+// the read, mirroring getProgramCountersForPackage's extraSkip.
+func (s *stackType) capture(extraSkip int) {
+	if s.programCounters != nil {
+		return
+	}
+	s.programCounters = getProgramCountersForPackage(extraSkip, s.pendingMaxDepth)
+	if s.lazy {
+		s.staleCapture = !seedStillOnStack(s.seedFuncName, s.programCounters)
+		s.captureDelay = time.Since(s.capturedAt)
+	}
+}
+
+// seedStillOnStack reports whether seedFuncName — the function that created
+// the error, resolved once up front in newLazyStackType — is still
+// somewhere in pcs. It's a proxy for "the frame that created this error
+// hasn't returned yet": if that function is gone from the stack, the
+// deferred capture is running long after the fact and won't reflect the
+// original call site.
+//
+// This has to compare resolved function names via runtime.CallersFrames,
+// not runtime.FuncForPC(pc).Entry(): the creating function is often tiny
+// enough to get inlined into its caller, and Func.Entry() then collapses
+// to the same address as that (still-live) caller, making an inlined,
+// already-returned call look indistinguishable from one that's still on
+// the stack. CallersFrames still resolves the distinct, precise name of
+// whatever was inlined at the original call site.
+func seedStillOnStack(seedFuncName string, pcs []uintptr) bool {
+	if seedFuncName == "" {
+		return true
+	}
+	frames := runtime.CallersFrames(pcs)
+	for {
+		frame, more := frames.Next()
+		if frame.Function == seedFuncName {
+			return true
+		}
+		if !more {
+			return false
+		}
+	}
+}
+
+// toFrames walks the captured program counters and returns them as Frame
+// values. Unlike formatVerboseStackTrace, this always walks a fresh
+// runtime.Frames iterator so it can be called any number of times.
+func (s *stackType) toFrames() []Frame {
+	s.capture(2)
+	frames := runtime.CallersFrames(s.programCounters)
+	result := make([]Frame, 0, len(s.programCounters))
+	for {
+		frame, more := frames.Next()
+		if s.include(frame) {
+			result = append(result, Frame{
+				Function: frame.Function,
+				File:     frame.File,
+				Line:     frame.Line,
+				PC:       frame.PC,
+			})
+		}
+		if !more {
+			break
+		}
+	}
+	return result
 }
 
 func funcName(fullyQualifiedFunctionName string) string {
@@ -19,18 +131,25 @@ func funcName(fullyQualifiedFunctionName string) string {
 }
 
 func (s *stackType) formatVerboseStackTrace(w io.Writer) {
+	s.capture(3)
+	if s.staleCapture {
+		fmt.Fprintf(w, "(lazy stack trace captured %s after creation, once its creating frame had already returned; frames below reflect where it was read, not where the error was created)\n", s.captureDelay.Round(time.Microsecond))
+	}
+
 	// Lazy load the frames when someone actually wants the stack trace.
 	if s.frames == nil {
 		s.frames = runtime.CallersFrames(s.programCounters)
 	}
 	for {
 		frame, more := s.frames.Next()
-		io.WriteString(w, funcName(frame.Function))
-		io.WriteString(w, "\n\t")
-		io.WriteString(w, frame.File)
-		io.WriteString(w, ":")
-		io.WriteString(w, strconv.Itoa(frame.Line))
-		io.WriteString(w, "\n")
+		if s.include(frame) {
+			io.WriteString(w, funcName(frame.Function))
+			io.WriteString(w, "\n\t")
+			io.WriteString(w, frame.File)
+			io.WriteString(w, ":")
+			io.WriteString(w, strconv.Itoa(frame.Line))
+			io.WriteString(w, "\n")
+		}
 		if !more {
 			break
 		}
@@ -41,6 +160,7 @@ func (s *stackType) formatVerboseStackTrace(w io.Writer) {
 // grab the stack trace for it.
 type wrappedErrorWithStackTrace struct {
 	err error
+	msg string
 	stk *stackType
 }
 
@@ -53,15 +173,16 @@ func (e *wrappedErrorWithStackTrace) Error() string {
 	return e.err.Error()
 }
 
+// StackTrace returns the frames captured when this error was wrapped.
+func (e *wrappedErrorWithStackTrace) StackTrace() []Frame {
+	return e.stk.toFrames()
+}
+
 func (e *wrappedErrorWithStackTrace) Format(state fmt.State, verb rune) {
 	switch verb {
 	case 'v':
 		if state.Flag('+') {
-			io.WriteString(state, e.err.Error())
-			// TODO: Unravel all the errors.
-			io.WriteString(state, "\n")
-			// TODO: Unravel all the stack traces.
-			e.stk.formatVerboseStackTrace(state)
+			formatChain(state, e)
 			return
 		}
 		fallthrough
@@ -72,6 +193,12 @@ func (e *wrappedErrorWithStackTrace) Format(state fmt.State, verb rune) {
 	}
 }
 
+// stack lets the rest of the package reach the captured stackType without
+// exposing it on the public StackTracer interface.
+func (e *wrappedErrorWithStackTrace) stack() *stackType {
+	return e.stk
+}
+
 // errorWithStackTrace is the base case for an error. This is when we create a
 // new error, if you are wrapping an error then use wrappedErrorWithStackTrace.
 type errorWithStackTrace struct {
@@ -84,13 +211,16 @@ func (e *errorWithStackTrace) Error() string {
 	return e.msg
 }
 
+// StackTrace returns the frames captured when this error was created.
+func (e *errorWithStackTrace) StackTrace() []Frame {
+	return e.stk.toFrames()
+}
+
 func (e *errorWithStackTrace) Format(state fmt.State, verb rune) {
 	switch verb {
 	case 'v':
 		if state.Flag('+') {
-			io.WriteString(state, e.msg)
-			io.WriteString(state, "\n")
-			e.stk.formatVerboseStackTrace(state)
+			formatChain(state, e)
 			return
 		}
 		fallthrough
@@ -101,11 +231,60 @@ func (e *errorWithStackTrace) Format(state fmt.State, verb rune) {
 	}
 }
 
+// stack lets the rest of the package reach the captured stackType without
+// exposing it on the public StackTracer interface.
+func (e *errorWithStackTrace) stack() *stackType {
+	return e.stk
+}
+
+// hasStack is implemented by both error types in this package that carry a
+// stack trace. It is unexported because the stackType it exposes is an
+// implementation detail; StackTracer is the public equivalent.
+type hasStack interface {
+	stack() *stackType
+}
+
+// formatChain walks the wrap chain of err via Unwrap, writing each level's
+// own message followed by its stack trace. Stacks are deduped: if a level
+// reused its inner error's stack (see stackTraceFor), it is only printed
+// once.
+func formatChain(w io.Writer, err error) {
+	var lastStack *stackType
+	for err != nil {
+		switch e := err.(type) {
+		case *wrappedErrorWithStackTrace:
+			io.WriteString(w, e.msg)
+			io.WriteString(w, "\n")
+			if e.stk != lastStack {
+				e.stk.formatVerboseStackTrace(w)
+				lastStack = e.stk
+			}
+			err = Unwrap(e.err)
+		case *errorWithStackTrace:
+			io.WriteString(w, e.msg)
+			io.WriteString(w, "\n")
+			if e.stk != lastStack {
+				e.stk.formatVerboseStackTrace(w)
+			}
+			err = nil
+		default:
+			// No stack trace of its own (e.g. a plain Wrap/fmt.Errorf chain,
+			// or an error from outside this package) — its Error() already
+			// contains everything beneath it, so stop here.
+			io.WriteString(w, err.Error())
+			io.WriteString(w, "\n")
+			err = nil
+		}
+	}
+}
+
 // getProgramCountersForPackage returns the program counters in context of this
-// package (skipping the callers internal to this package)
-func getProgramCountersForPackage() []uintptr {
-	// We need to choose the maximum depth in the stack.
-	//
+// package (skipping the callers internal to this package). extraSkip lets a
+// caller that sits behind an extra layer of indirection (e.g. stackTraceFor)
+// account for its own stack frame on top of the usual three. maxDepth caps
+// how many frames are captured; callers normally pass defaultMaxStackDepth()
+// so SetMaxStackDepth/WithMaxDepth take effect.
+func getProgramCountersForPackage(extraSkip, maxDepth int) []uintptr {
 	// Most Go programs don't go deep in to the call stack. Most of the time
 	// engineers are looking at the first line; in other cases they may look for
 	// some context. Ideally, engineers are using logs and error messages to
@@ -114,11 +293,12 @@ func getProgramCountersForPackage() []uintptr {
 	// In those case, we need to ensure that a sufficient stack trace is
 	// available. In my experience, it is rare to need more than a handful of
 	// calls before you are able to determine the call path that you need to
-	// focus on debugging. So, we will limit this to a smaller number.
-	//
-	// 32 was chosen because of the above and to match github.com/pkg/errors
-	const maxStackTraceDepth = 32
-	var programCounter [maxStackTraceDepth]uintptr
+	// focus on debugging. So, we will limit this to a smaller number by
+	// default (see defaultStackTraceDepth).
+	if maxDepth <= 0 {
+		maxDepth = defaultStackTraceDepth
+	}
+	programCounter := make([]uintptr, maxDepth)
 
 	// We use 3 to skip the right amount of callers; we want to skip all the
 	// internal function calls to this package. That way the users first line is
@@ -126,9 +306,9 @@ func getProgramCountersForPackage() []uintptr {
 	//
 	//  1. runtime.Callers
 	//  2. getProgramCountersForPackage
-	//  3. NewWithStackTrace
+	//  3. NewWithStackTrace (or whichever exported function called us)
 	const skipInternalFunctions = 3
-	actualDepth := runtime.Callers(skipInternalFunctions, programCounter[:])
+	actualDepth := runtime.Callers(skipInternalFunctions+extraSkip, programCounter)
 
 	// We want to set the capacity to avoid ownership/mutability issues.
 	return programCounter[0:actualDepth:actualDepth]
@@ -147,20 +327,33 @@ func Wrapf(err error, format string, a ...any) error {
 func WrapWithStackTrace(err error, message string) error {
 	return &wrappedErrorWithStackTrace{
 		err: Wrap(err, message),
-		stk: &stackType{
-			programCounters: getProgramCountersForPackage(),
-			frames:          nil,
-		},
+		msg: message,
+		stk: stackTraceFor(err),
 	}
 }
 
 func WrapWithStackTracef(err error, format string, a ...any) error {
+	message := fmt.Sprintf(format, a...)
 	return &wrappedErrorWithStackTrace{
-		err: Wrapf(err, format, a...),
-		stk: &stackType{
-			programCounters: getProgramCountersForPackage(),
-			frames:          nil,
-		},
+		err: Wrap(err, message),
+		msg: message,
+		stk: stackTraceFor(err),
+	}
+}
+
+// stackTraceFor returns the stackType to attach when wrapping err. If err
+// already carries one of our stack traces (directly, or further down its
+// Unwrap chain) we reuse it instead of capturing a new one, so %+v doesn't
+// print the same frames twice for a wrap site that didn't add any new call
+// depth.
+func stackTraceFor(err error) *stackType {
+	var inner hasStack
+	if stderrs.As(err, &inner) {
+		return inner.stack()
+	}
+	return &stackType{
+		programCounters: getProgramCountersForPackage(1, defaultMaxStackDepth()),
+		filter:          currentFrameFilter(),
 	}
 }
 
@@ -174,9 +367,28 @@ func Is(err error, target error) bool {
 	return stderrs.Is(err, target)
 }
 
-// Join is a proxy to standard errors.Join
+// Join combines the non-nil errors in err, like standard errors.Join, but
+// returns our own joinedError so %+v prints each error's own message and
+// stack trace and so StackTrace()/Fields() can aggregate across them. It
+// returns nil if every error is nil, and the error itself if only one is
+// non-nil.
 func Join(err ...error) error {
-	return stderrs.Join(err...)
+	nonNil := make([]error, 0, len(err))
+	for _, e := range err {
+		if e != nil {
+			nonNil = append(nonNil, e)
+		}
+	}
+	switch len(nonNil) {
+	case 0:
+		return nil
+	case 1:
+		// Nothing to join — hand back the single survivor directly rather
+		// than wrapping it in a joinedError of one.
+		return nonNil[0]
+	default:
+		return &joinedError{errs: nonNil}
+	}
 }
 
 // New is a proxy to standard errors.New
@@ -193,8 +405,8 @@ func NewWithStackTrace(text string) error {
 	return &errorWithStackTrace{
 		msg: text,
 		stk: &stackType{
-			programCounters: getProgramCountersForPackage(),
-			frames:          nil,
+			programCounters: getProgramCountersForPackage(0, defaultMaxStackDepth()),
+			filter:          currentFrameFilter(),
 		},
 	}
 }
@@ -203,12 +415,22 @@ func NewWithStackTracef(format string, a ...any) error {
 	return &errorWithStackTrace{
 		msg: fmt.Sprintf(format, a...),
 		stk: &stackType{
-			programCounters: getProgramCountersForPackage(),
-			frames:          nil,
+			programCounters: getProgramCountersForPackage(0, defaultMaxStackDepth()),
+			filter:          currentFrameFilter(),
 		},
 	}
 }
 
+// NewWithStackTraceOpts is NewWithStackTrace with per-call control over skip
+// depth, max depth, and frame filtering — see WithSkip, WithMaxDepth, and
+// WithFilter.
+func NewWithStackTraceOpts(text string, opts ...Option) error {
+	return &errorWithStackTrace{
+		msg: text,
+		stk: newStackTypeWithOpts(1, opts),
+	}
+}
+
 // Unwrap is a proxy to errors.Unwrap
 func Unwrap(err error) error {
 	return stderrs.Unwrap(err)
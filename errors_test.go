@@ -156,6 +156,75 @@ func TestNewWithStackTrace_StackTraceDepth(t *testing.T) {
 	}
 }
 
+func TestStackTrace(t *testing.T) {
+	tests := []struct {
+		name     string
+		provider func() error
+	}{
+		{
+			name: "NewWithStackTrace",
+			provider: func() error {
+				return NewWithStackTrace("MyError")
+			},
+		},
+		{
+			name: "WrapWithStackTrace",
+			provider: func() error {
+				return WrapWithStackTrace(New("inner"), "outer")
+			},
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Arrange
+			err := testCase.provider()
+
+			// Act
+			tracer, ok := err.(StackTracer)
+
+			// Assert
+			assert.True(t, ok)
+			frames := tracer.StackTrace()
+			assert.NotEmpty(t, frames)
+			assert.Contains(t, frames[0].Function, "errors.TestStackTrace")
+			assert.NotZero(t, frames[0].Line)
+			assert.NotZero(t, frames[0].PC)
+
+			// Calling StackTrace() again should be stable.
+			assert.Equal(t, frames, tracer.StackTrace())
+		})
+	}
+}
+
+func TestWrapWithStackTrace_ReusesInnerStack(t *testing.T) {
+	// Arrange
+	inner := NewWithStackTrace("inner")
+	outer := WrapWithStackTrace(inner, "outer")
+
+	// Act
+	innerTracer := inner.(StackTracer)
+	outerTracer := outer.(StackTracer)
+
+	// Assert
+	assert.Equal(t, innerTracer.StackTrace(), outerTracer.StackTrace())
+}
+
+func TestWrapWithStackTrace_FormatVerbose_UnravelsChain(t *testing.T) {
+	// Arrange
+	inner := NewWithStackTrace("inner")
+	outer := WrapWithStackTrace(inner, "outer")
+
+	// Act
+	result := fmt.Sprintf("%+v", outer)
+
+	// Assert: the outer message is followed by the (single, shared) stack
+	// trace, and the inner message still shows up further down the chain.
+	assert.True(t, strings.HasPrefix(result, "outer\nerrors.TestWrapWithStackTrace_FormatVerbose_UnravelsChain"))
+	assert.True(t, strings.HasSuffix(result, "inner\n"))
+	assert.Equal(t, 1, strings.Count(result, "testing.tRunner"))
+}
+
 func TestWrap(t *testing.T) {
 	tests := []struct {
 		name     string
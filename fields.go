@@ -0,0 +1,130 @@
+package errors
+
+import (
+	"encoding/json"
+	stderrs "errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+)
+
+// errorWithFields attaches structured key/value context to an error. Fields
+// survive wrapping and can be collected from anywhere in the chain with
+// Fields(err).
+type errorWithFields struct {
+	err    error
+	fields map[string]any
+}
+
+// Error just returns the Error().
+func (e *errorWithFields) Error() string {
+	return e.err.Error()
+}
+
+func (e *errorWithFields) Unwrap() error {
+	return e.err
+}
+
+// Format delegates to the wrapped error's Format when it has one (so %+v
+// still unravels the message/stack chain underneath), otherwise falls back
+// to Error().
+func (e *errorWithFields) Format(state fmt.State, verb rune) {
+	if formatter, ok := e.err.(fmt.Formatter); ok {
+		formatter.Format(state, verb)
+		return
+	}
+	switch verb {
+	case 'v', 's':
+		io.WriteString(state, e.err.Error())
+	case 'q':
+		fmt.Fprintf(state, "%q", e.err.Error())
+	}
+}
+
+// LogValue lets slog render the message, stack trace (if any), and attached
+// fields (nested under "fields") as a structured group instead of falling
+// back to Error().
+func (e *errorWithFields) LogValue() slog.Value {
+	attrs := make([]slog.Attr, 0, 3)
+	attrs = append(attrs, slog.String("msg", e.Error()))
+
+	var tracer StackTracer
+	if stderrs.As(error(e), &tracer) {
+		attrs = append(attrs, slog.Any("stack", tracer.StackTrace()))
+	}
+
+	fields := Fields(e)
+	fieldAttrs := make([]slog.Attr, 0, len(fields))
+	for key, value := range fields {
+		fieldAttrs = append(fieldAttrs, slog.Any(key, value))
+	}
+	attrs = append(attrs, slog.Attr{Key: "fields", Value: slog.GroupValue(fieldAttrs...)})
+	return slog.GroupValue(attrs...)
+}
+
+// MarshalJSON emits {msg, stack, fields} so JSON-based log sinks get the
+// same structure as LogValue.
+func (e *errorWithFields) MarshalJSON() ([]byte, error) {
+	payload := struct {
+		Msg    string         `json:"msg"`
+		Stack  []Frame        `json:"stack,omitempty"`
+		Fields map[string]any `json:"fields,omitempty"`
+	}{
+		Msg:    e.Error(),
+		Fields: Fields(e),
+	}
+
+	var tracer StackTracer
+	if stderrs.As(error(e), &tracer) {
+		payload.Stack = tracer.StackTrace()
+	}
+
+	return json.Marshal(payload)
+}
+
+// WithField is a proxy to WithFields for a single key/value pair.
+func WithField(err error, key string, value any) error {
+	return WithFields(err, map[string]any{key: value})
+}
+
+// WithFields wraps err with a key/value context frame. The fields are
+// copied, so mutating the map passed in afterwards has no effect.
+func WithFields(err error, fields map[string]any) error {
+	copied := make(map[string]any, len(fields))
+	maps.Copy(copied, fields)
+	return &errorWithFields{err: err, fields: copied}
+}
+
+// Fields walks err's chain (including branching into any joined errors)
+// collecting every attached field. Where the same key was attached more
+// than once, the outermost value wins.
+func Fields(err error) map[string]any {
+	result := map[string]any{}
+	collectFields(err, result)
+	return result
+}
+
+// collectFields recurses to the bottom of err's chain before merging fields
+// back up, so that an outer WithFields call overwrites whatever an inner one
+// set for the same key.
+func collectFields(err error, result map[string]any) {
+	if err == nil {
+		return
+	}
+
+	if fe, ok := err.(*errorWithFields); ok {
+		collectFields(fe.err, result)
+		maps.Copy(result, fe.fields)
+		return
+	}
+
+	if multi, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, child := range multi.Unwrap() {
+			collectFields(child, result)
+		}
+		return
+	}
+
+	collectFields(Unwrap(err), result)
+}
@@ -0,0 +1,94 @@
+package errors
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFields(t *testing.T) {
+	tests := []struct {
+		name     string
+		provider func() error
+		expected map[string]any
+	}{
+		{
+			name: "single field",
+			provider: func() error {
+				return WithField(New("boom"), "user_id", 42)
+			},
+			expected: map[string]any{"user_id": 42},
+		},
+		{
+			name: "multiple fields merged across wraps",
+			provider: func() error {
+				err := New("boom")
+				err = WithField(err, "user_id", 42)
+				err = Wrap(err, "loading widget")
+				err = WithField(err, "widget_id", "abc")
+				return err
+			},
+			expected: map[string]any{"user_id": 42, "widget_id": "abc"},
+		},
+		{
+			name: "outer value wins",
+			provider: func() error {
+				err := New("boom")
+				err = WithField(err, "user_id", 1)
+				err = WithField(err, "user_id", 2)
+				return err
+			},
+			expected: map[string]any{"user_id": 2},
+		},
+		{
+			name: "no fields attached",
+			provider: func() error {
+				return New("boom")
+			},
+			expected: map[string]any{},
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Arrange
+			err := testCase.provider()
+
+			// Act
+			fields := Fields(err)
+
+			// Assert
+			assert.Equal(t, testCase.expected, fields)
+		})
+	}
+}
+
+func TestErrorWithFields_Error(t *testing.T) {
+	// Arrange
+	err := WithField(New("boom"), "user_id", 42)
+
+	// Act + Assert
+	assert.Equal(t, "boom", err.Error())
+}
+
+func TestErrorWithFields_MarshalJSON(t *testing.T) {
+	// Arrange
+	err := WithField(NewWithStackTrace("boom"), "user_id", 42)
+
+	// Act
+	data, marshalErr := json.Marshal(err)
+
+	// Assert
+	assert.NoError(t, marshalErr)
+
+	var payload struct {
+		Msg    string         `json:"msg"`
+		Stack  []Frame        `json:"stack"`
+		Fields map[string]any `json:"fields"`
+	}
+	assert.NoError(t, json.Unmarshal(data, &payload))
+	assert.Equal(t, "boom", payload.Msg)
+	assert.NotEmpty(t, payload.Stack)
+	assert.Equal(t, float64(42), payload.Fields["user_id"])
+}
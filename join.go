@@ -0,0 +1,101 @@
+package errors
+
+import (
+	stderrs "errors"
+	"fmt"
+	"io"
+)
+
+// joinedError is the result of Join. It keeps the individual errors around
+// (instead of flattening them into one string like standard errors.Join)
+// so formatting and the accessors elsewhere in this package can still see
+// each one.
+type joinedError struct {
+	errs []error
+}
+
+// Error joins each error's message with a newline, matching standard
+// errors.Join.
+func (e *joinedError) Error() string {
+	return stderrs.Join(e.errs...).Error()
+}
+
+// Unwrap exposes the joined errors so standard errors.Is/As (and ours, which
+// proxy to them) walk into every one of them.
+func (e *joinedError) Unwrap() []error {
+	return e.errs
+}
+
+// Is reports whether target matches any of the joined errors.
+func (e *joinedError) Is(target error) bool {
+	for _, err := range e.errs {
+		if stderrs.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As reports whether target matches any of the joined errors.
+func (e *joinedError) As(target any) bool {
+	for _, err := range e.errs {
+		if stderrs.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// StackTrace aggregates the stack trace of every joined error that has one,
+// in order, so a joinedError itself satisfies StackTracer.
+func (e *joinedError) StackTrace() []Frame {
+	var frames []Frame
+	for _, err := range e.errs {
+		var tracer StackTracer
+		if stderrs.As(err, &tracer) {
+			frames = append(frames, tracer.StackTrace()...)
+		}
+	}
+	return frames
+}
+
+// Format prints each joined error's own message and stack trace in turn,
+// separated so they don't run together.
+func (e *joinedError) Format(state fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if state.Flag('+') {
+			for i, err := range e.errs {
+				if i > 0 {
+					io.WriteString(state, "---\n")
+				}
+				formatChain(state, err)
+			}
+			return
+		}
+		fallthrough
+	case 's':
+		io.WriteString(state, e.Error())
+	case 'q':
+		fmt.Fprintf(state, "%q", e.Error())
+	}
+}
+
+// AppendInto accumulates err into *target: if *target is nil it is set
+// directly, otherwise the two are combined with Join. This is meant for
+// loops that gather errors as they go:
+//
+//	var result error
+//	for _, item := range items {
+//		errors.AppendInto(&result, process(item))
+//	}
+func AppendInto(target *error, err error) {
+	if err == nil {
+		return
+	}
+	if *target == nil {
+		*target = err
+		return
+	}
+	*target = Join(*target, err)
+}
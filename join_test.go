@@ -0,0 +1,97 @@
+package errors
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJoin_Is(t *testing.T) {
+	// Arrange
+	a := New("a")
+	b := New("b")
+	joined := Join(a, b)
+
+	// Act + Assert
+	assert.True(t, Is(joined, a))
+	assert.True(t, Is(joined, b))
+	assert.False(t, Is(joined, New("c")))
+}
+
+func TestJoin_NilsAreDropped(t *testing.T) {
+	// Arrange
+	a := New("a")
+
+	// Act
+	joined := Join(nil, a, nil)
+
+	// Assert
+	assert.Equal(t, a, joined)
+	assert.Nil(t, Join(nil, nil))
+}
+
+func TestJoin_FormatVerbose(t *testing.T) {
+	// Arrange
+	a := NewWithStackTrace("a")
+	b := New("b")
+	joined := Join(a, b)
+
+	// Act
+	result := fmt.Sprintf("%+v", joined)
+
+	// Assert
+	assert.True(t, strings.HasPrefix(result, "a\nerrors.TestJoin_FormatVerbose"))
+	assert.Contains(t, result, "---\n")
+	assert.True(t, strings.HasSuffix(result, "b\n"))
+}
+
+func TestJoin_StackTraceAggregatesSiblings(t *testing.T) {
+	// Arrange
+	a := NewWithStackTrace("a")
+	b := NewWithStackTrace("b")
+	joined := Join(a, b)
+
+	// Act
+	tracer, ok := joined.(StackTracer)
+
+	// Assert
+	assert.True(t, ok)
+	frames := tracer.StackTrace()
+	assert.Equal(t, a.(StackTracer).StackTrace(), frames[:len(frames)/2])
+	assert.Equal(t, b.(StackTracer).StackTrace(), frames[len(frames)/2:])
+}
+
+func TestAppendInto(t *testing.T) {
+	// Arrange
+	var result error
+	a := New("a")
+	b := New("b")
+
+	// Act
+	AppendInto(&result, nil)
+	assert.NoError(t, result)
+
+	AppendInto(&result, a)
+	assert.Equal(t, a, result)
+
+	AppendInto(&result, b)
+
+	// Assert
+	assert.True(t, Is(result, a))
+	assert.True(t, Is(result, b))
+}
+
+func TestFields_AggregatesAcrossJoin(t *testing.T) {
+	// Arrange
+	a := WithField(New("a"), "x", 1)
+	b := WithField(New("b"), "y", 2)
+	joined := Join(a, b)
+
+	// Act
+	fields := Fields(joined)
+
+	// Assert
+	assert.Equal(t, map[string]any{"x": 1, "y": 2}, fields)
+}
@@ -0,0 +1,80 @@
+package errors
+
+import (
+	stderrs "errors"
+	"runtime"
+	"time"
+)
+
+// NewWithStackTraceLazy is NewWithStackTrace, but defers the expensive
+// runtime.Callers walk until the trace is actually read (via StackTrace(),
+// %+v, or Freeze). Creation only captures a cheap seed: a single PC for the
+// immediate caller and a timestamp.
+//
+// A goroutine's stack is only valid while it's still running the frame that
+// created the error. Reading the trace synchronously, soon after creating
+// the error, sees the same frames an eager capture would have. But if enough
+// of the call stack unwinds — the creating frame returns, the error crosses
+// a goroutine boundary — before anything reads the trace, the deferred
+// capture runs against whatever the stack looks like *then*, not where the
+// error was created; the seed PC is used to detect that and warn about it
+// (see seedStillOnStack). Call Freeze(err) at a boundary like that to force
+// capture while the original frames are still live.
+func NewWithStackTraceLazy(text string) error {
+	return &errorWithStackTrace{
+		msg: text,
+		stk: newLazyStackType(0),
+	}
+}
+
+// WrapWithStackTraceLazy is WrapWithStackTrace, but defers capture the same
+// way NewWithStackTraceLazy does. If err already carries a stack (lazy or
+// not), that stack is reused instead — see stackTraceFor.
+func WrapWithStackTraceLazy(err error, message string) error {
+	return &wrappedErrorWithStackTrace{
+		err: Wrap(err, message),
+		msg: message,
+		stk: stackTraceForLazy(err),
+	}
+}
+
+func stackTraceForLazy(err error) *stackType {
+	var inner hasStack
+	if stderrs.As(err, &inner) {
+		return inner.stack()
+	}
+	return newLazyStackType(1)
+}
+
+func newLazyStackType(extraSkip int) *stackType {
+	var pc [1]uintptr
+	const skipInternalFunctions = 3
+	runtime.Callers(skipInternalFunctions+extraSkip, pc[:])
+
+	// Resolve the name now, rather than keeping the raw PC around: see
+	// seedStillOnStack for why a name comparison is needed instead of one
+	// against Func.Entry().
+	frame, _ := runtime.CallersFrames(pc[:]).Next()
+
+	return &stackType{
+		lazy:            true,
+		pendingMaxDepth: defaultMaxStackDepth(),
+		filter:          currentFrameFilter(),
+		seedFuncName:    frame.Function,
+		capturedAt:      time.Now(),
+	}
+}
+
+// Freeze forces immediate capture of a lazily-created stack trace, returning
+// err unchanged. Errors without a lazy stack (or without a stack at all)
+// pass through untouched. Call this right before an error might cross a
+// goroutine boundary — a channel send, a callback run later, a goroutine
+// pool — so the trace reflects where it was created instead of wherever it
+// eventually gets read.
+func Freeze(err error) error {
+	var hs hasStack
+	if stderrs.As(err, &hs) {
+		hs.stack().capture(1)
+	}
+	return err
+}
@@ -0,0 +1,28 @@
+package errors
+
+import "testing"
+
+// BenchmarkNewWithStackTrace captures the full stack eagerly, like pkg/errors.
+func BenchmarkNewWithStackTrace(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_ = NewWithStackTrace("boom")
+	}
+}
+
+// BenchmarkNewWithStackTraceLazy only captures a single-frame seed up front,
+// deferring the full walk until something actually reads the trace.
+func BenchmarkNewWithStackTraceLazy(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_ = NewWithStackTraceLazy("boom")
+	}
+}
+
+// BenchmarkNewWithStackTraceLazy_ReadImmediately shows the cost when the
+// caller reads the trace right away (the worst case for the lazy variant,
+// since it pays the deferred capture on top of the seed).
+func BenchmarkNewWithStackTraceLazy_ReadImmediately(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		err := NewWithStackTraceLazy("boom")
+		_ = err.(StackTracer).StackTrace()
+	}
+}
@@ -0,0 +1,92 @@
+package errors
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewWithStackTraceLazy_CapturesOnFirstRead(t *testing.T) {
+	// Arrange
+	err := NewWithStackTraceLazy("boom")
+
+	// Act
+	frames := err.(StackTracer).StackTrace()
+
+	// Assert
+	assert.NotEmpty(t, frames)
+	assert.Contains(t, frames[0].Function, "errors.TestNewWithStackTraceLazy_CapturesOnFirstRead")
+
+	// Reading again returns the same (now-captured) frames.
+	assert.Equal(t, frames, err.(StackTracer).StackTrace())
+}
+
+func TestWrapWithStackTraceLazy_ReusesInnerStack(t *testing.T) {
+	// Arrange
+	inner := NewWithStackTraceLazy("inner")
+	outer := WrapWithStackTraceLazy(inner, "outer")
+
+	// Act
+	innerFrames := inner.(StackTracer).StackTrace()
+	outerFrames := outer.(StackTracer).StackTrace()
+
+	// Assert
+	assert.Equal(t, innerFrames, outerFrames)
+}
+
+func TestFreeze_ForcesCaptureImmediately(t *testing.T) {
+	// Arrange
+	err := NewWithStackTraceLazy("boom")
+
+	// Act
+	frozen := Freeze(err)
+
+	// Assert: capture already happened, so reading the trace later doesn't
+	// change it even if called from a helper at a different depth.
+	before := frozen.(StackTracer).StackTrace()
+	after := readStackTraceFromHelper(frozen)
+	assert.Equal(t, before, after)
+}
+
+func readStackTraceFromHelper(err error) []Frame {
+	return err.(StackTracer).StackTrace()
+}
+
+func TestFreeze_PassesThroughErrorsWithoutAStack(t *testing.T) {
+	// Arrange
+	err := New("boom")
+
+	// Act
+	result := Freeze(err)
+
+	// Assert
+	assert.Equal(t, err, result)
+}
+
+func TestNewWithStackTraceLazy_NoStaleWarningWhenReadImmediately(t *testing.T) {
+	// Arrange
+	err := NewWithStackTraceLazy("boom")
+
+	// Act
+	result := fmt.Sprintf("%+v", err)
+
+	// Assert
+	assert.NotContains(t, result, "creating frame had already returned")
+}
+
+func newLazyErrorInHelper() error {
+	return NewWithStackTraceLazy("boom")
+}
+
+func TestNewWithStackTraceLazy_WarnsWhenCapturedAfterCreatingFrameReturns(t *testing.T) {
+	// Arrange: by the time we read the trace, newLazyErrorInHelper has
+	// already returned, so the deferred capture can't see its frame.
+	err := newLazyErrorInHelper()
+
+	// Act
+	result := fmt.Sprintf("%+v", err)
+
+	// Assert
+	assert.Contains(t, result, "creating frame had already returned")
+}
@@ -0,0 +1,106 @@
+package errors
+
+import (
+	"runtime"
+	"sync/atomic"
+)
+
+// defaultStackTraceDepth is the starting point for SetMaxStackDepth. 32 was
+// chosen to match github.com/pkg/errors; see getProgramCountersForPackage.
+const defaultStackTraceDepth = 32
+
+var maxStackDepth atomic.Int64
+
+func init() {
+	maxStackDepth.Store(defaultStackTraceDepth)
+}
+
+// SetMaxStackDepth changes how many frames NewWithStackTrace and
+// WrapWithStackTrace capture by default. It is safe to call concurrently
+// with error creation. Use WithMaxDepth on NewWithStackTraceOpts to override
+// it for a single call instead.
+func SetMaxStackDepth(n int) {
+	maxStackDepth.Store(int64(n))
+}
+
+func defaultMaxStackDepth() int {
+	return int(maxStackDepth.Load())
+}
+
+var frameFilter atomic.Pointer[func(runtime.Frame) bool]
+
+// SetFrameFilter installs a package-wide filter applied when a stack trace
+// is formatted or read with StackTrace(). Frames for which fn returns false
+// are omitted. Pass nil to clear it.
+//
+// The filter is applied lazily, at format/StackTrace time rather than at
+// capture time, so it never affects capture cost — it's meant for hiding
+// noise (vendored frames, generated gRPC stubs, testing.tRunner) from the
+// final output, not for reducing how much is captured. Use WithFilter on
+// NewWithStackTraceOpts to override it for a single call.
+func SetFrameFilter(fn func(runtime.Frame) bool) {
+	if fn == nil {
+		frameFilter.Store(nil)
+		return
+	}
+	frameFilter.Store(&fn)
+}
+
+func currentFrameFilter() func(runtime.Frame) bool {
+	p := frameFilter.Load()
+	if p == nil {
+		return nil
+	}
+	return *p
+}
+
+// stackOptions holds the per-call overrides assembled from a list of Option.
+type stackOptions struct {
+	skip     int
+	maxDepth int
+	filter   func(runtime.Frame) bool
+}
+
+// Option configures a single NewWithStackTraceOpts call.
+type Option func(*stackOptions)
+
+// WithSkip skips n additional frames beyond the ones this package already
+// skips internally. Use it when NewWithStackTraceOpts is called from inside
+// your own helper and you want the stack to start at the helper's caller.
+func WithSkip(n int) Option {
+	return func(o *stackOptions) {
+		o.skip = n
+	}
+}
+
+// WithMaxDepth overrides SetMaxStackDepth for a single call.
+func WithMaxDepth(n int) Option {
+	return func(o *stackOptions) {
+		o.maxDepth = n
+	}
+}
+
+// WithFilter overrides SetFrameFilter for a single call.
+func WithFilter(fn func(runtime.Frame) bool) Option {
+	return func(o *stackOptions) {
+		o.filter = fn
+	}
+}
+
+// newStackTypeWithOpts captures a stack trace honouring opts, falling back to
+// the package-level defaults (SetMaxStackDepth/SetFrameFilter) for anything
+// opts doesn't override. extraSkip is the same "extra layer of indirection"
+// accounting that getProgramCountersForPackage takes.
+func newStackTypeWithOpts(extraSkip int, opts []Option) *stackType {
+	o := stackOptions{
+		maxDepth: defaultMaxStackDepth(),
+		filter:   currentFrameFilter(),
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return &stackType{
+		programCounters: getProgramCountersForPackage(extraSkip+o.skip, o.maxDepth),
+		filter:          o.filter,
+	}
+}
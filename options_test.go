@@ -0,0 +1,61 @@
+package errors
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetMaxStackDepth(t *testing.T) {
+	// Arrange
+	defer SetMaxStackDepth(defaultStackTraceDepth)
+	SetMaxStackDepth(2)
+
+	// Act
+	err := NewWithStackTrace("boom")
+
+	// Assert
+	assert.Len(t, err.(StackTracer).StackTrace(), 2)
+}
+
+func TestNewWithStackTraceOpts_WithMaxDepth(t *testing.T) {
+	// Arrange
+	err := NewWithStackTraceOpts("boom", WithMaxDepth(1))
+
+	// Act
+	frames := err.(StackTracer).StackTrace()
+
+	// Assert
+	assert.Len(t, frames, 1)
+	assert.Contains(t, frames[0].Function, "errors.TestNewWithStackTraceOpts_WithMaxDepth")
+}
+
+func TestNewWithStackTraceOpts_WithFilter(t *testing.T) {
+	// Arrange
+	err := NewWithStackTraceOpts("boom", WithFilter(func(f runtime.Frame) bool {
+		return !strings.Contains(f.Function, "testing.tRunner")
+	}))
+
+	// Act
+	result := fmt.Sprintf("%+v", err)
+
+	// Assert
+	assert.NotContains(t, result, "testing.tRunner")
+}
+
+func TestSetFrameFilter(t *testing.T) {
+	// Arrange
+	defer SetFrameFilter(nil)
+	SetFrameFilter(func(f runtime.Frame) bool {
+		return !strings.Contains(f.Function, "testing.tRunner")
+	})
+
+	// Act
+	result := fmt.Sprintf("%+v", NewWithStackTrace("boom"))
+
+	// Assert
+	assert.NotContains(t, result, "testing.tRunner")
+}
@@ -0,0 +1,106 @@
+package errors
+
+import (
+	stderrs "errors"
+	"fmt"
+	"sync"
+)
+
+// sentinelError is a typed error with a stable (codespace, code) identity,
+// modeled on the Cosmos SDK errors package. Wrapping a sentinel (via Wrap,
+// Wrapf, WrapWithStackTrace, ...) keeps the identity reachable with Is/As,
+// so callers can build a machine-readable error taxonomy on top of the
+// string-only API.
+type sentinelError struct {
+	codespace   string
+	code        uint32
+	description string
+}
+
+// Error returns the registered description.
+func (e *sentinelError) Error() string {
+	return e.description
+}
+
+// Is reports whether target is a sentinel with the same codespace and code.
+func (e *sentinelError) Is(target error) bool {
+	other, ok := target.(*sentinelError)
+	if !ok {
+		return false
+	}
+	return e.codespace == other.codespace && e.code == other.code
+}
+
+var (
+	registryMu sync.Mutex
+	registry   = map[string]map[uint32]*sentinelError{}
+)
+
+// Register creates and registers a sentinel error under (codespace, code).
+// It panics if that pair has already been registered, since two unrelated
+// errors silently comparing equal under Is would be a bigger problem than a
+// noisy failure at init time.
+func Register(codespace string, code uint32, description string) error {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	codes, ok := registry[codespace]
+	if !ok {
+		codes = map[uint32]*sentinelError{}
+		registry[codespace] = codes
+	}
+
+	if existing, ok := codes[code]; ok {
+		panic(fmt.Sprintf("errors: code %d already registered in codespace %q as %q", code, codespace, existing.description))
+	}
+
+	err := &sentinelError{codespace: codespace, code: code, description: description}
+	codes[code] = err
+	return err
+}
+
+// Sentinel is a proxy to Register. Use it at package scope to declare a
+// typed, ABCI-style error:
+//
+//	var ErrNotFound = errors.Sentinel("mymodule", 1, "not found")
+func Sentinel(codespace string, code uint32, description string) error {
+	return Register(codespace, code, description)
+}
+
+// Code returns the code of the sentinel error anywhere in err's chain, or 0
+// if none is found.
+func Code(err error) uint32 {
+	var s *sentinelError
+	if stderrs.As(err, &s) {
+		return s.code
+	}
+	return 0
+}
+
+// Codespace returns the codespace of the sentinel error anywhere in err's
+// chain, or "" if none is found.
+func Codespace(err error) string {
+	var s *sentinelError
+	if stderrs.As(err, &s) {
+		return s.codespace
+	}
+	return ""
+}
+
+// ABCIInfo extracts the codespace and code of the sentinel error in err's
+// chain (if any) along with a log message: the sanitized Error() string when
+// debug is false, or the full %+v (message chain plus stack traces) when
+// debug is true.
+func ABCIInfo(err error, debug bool) (codespace string, code uint32, log string) {
+	if err == nil {
+		return "", 0, ""
+	}
+
+	codespace = Codespace(err)
+	code = Code(err)
+
+	if debug {
+		return codespace, code, fmt.Sprintf("%+v", err)
+	}
+	return codespace, code, err.Error()
+}
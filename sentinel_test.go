@@ -0,0 +1,69 @@
+package errors
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSentinel_CodeAndCodespace(t *testing.T) {
+	// Arrange
+	errNotFound := Sentinel("mymodule", 1, "not found")
+	wrapped := Wrap(errNotFound, "loading widget")
+	wrappedWithStack := WrapWithStackTrace(errNotFound, "loading widget")
+
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{name: "sentinel", err: errNotFound},
+		{name: "Wrap", err: wrapped},
+		{name: "WrapWithStackTrace", err: wrappedWithStack},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Act + Assert
+			assert.Equal(t, uint32(1), Code(testCase.err))
+			assert.Equal(t, "mymodule", Codespace(testCase.err))
+			assert.True(t, Is(testCase.err, errNotFound))
+		})
+	}
+}
+
+func TestSentinel_DistinctCodesDoNotMatch(t *testing.T) {
+	// Arrange
+	errNotFound := Sentinel("mymodule", 2, "not found")
+	errInvalid := Sentinel("mymodule", 3, "invalid")
+
+	// Act + Assert
+	assert.False(t, Is(errNotFound, errInvalid))
+	assert.False(t, Is(errInvalid, errNotFound))
+}
+
+func TestRegister_PanicsOnDuplicate(t *testing.T) {
+	// Arrange
+	Register("duplicatetest", 1, "first")
+
+	// Act + Assert
+	assert.Panics(t, func() {
+		Register("duplicatetest", 1, "second")
+	})
+}
+
+func TestABCIInfo(t *testing.T) {
+	// Arrange
+	errNotFound := Sentinel("mymodule", 4, "not found")
+	wrapped := WrapWithStackTrace(errNotFound, "loading widget")
+
+	// Act
+	codespace, code, log := ABCIInfo(wrapped, false)
+	_, _, debugLog := ABCIInfo(wrapped, true)
+
+	// Assert
+	assert.Equal(t, "mymodule", codespace)
+	assert.Equal(t, uint32(4), code)
+	assert.Equal(t, "loading widget: not found", log)
+	assert.Contains(t, debugLog, "loading widget")
+	assert.Contains(t, debugLog, "errors.TestABCIInfo")
+}